@@ -6,10 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
-	"os/exec"
 	"regexp"
-	"runtime"
 	"time"
 )
 
@@ -21,20 +18,47 @@ type VM struct {
 	VMID int    `json:"vmid"`
 }
 
+// defaultRefreshInterval is used by StartTokenRefreshLoop when
+// Client.RefreshInterval is unset.
+const defaultRefreshInterval = 10 * time.Minute
+
 // Client manages communication with the ZeroSpice server
 type Client struct {
 	ServerURL    string
 	Username     string
 	SessionToken string
 	httpClient   *http.Client
+
+	// PreferredViewer names the Viewer to try first in LaunchSpiceViewer.
+	// Empty means auto-detect. See AppConfig.PreferredViewer.
+	PreferredViewer string
+
+	// RefreshInterval overrides how often StartTokenRefreshLoop refreshes
+	// the session token. Zero means use defaultRefreshInterval. See
+	// AppConfig.RefreshIntervalMinutes.
+	RefreshInterval time.Duration
+
+	// LastRefresh records when RefreshToken last succeeded, so GUI mode
+	// can show a live "refreshed N ago" status.
+	LastRefresh time.Time
 }
 
-// NewClient creates a new ZeroSpice client
+// NewClient creates a new ZeroSpice client with no TLS pinning.
 func NewClient(serverURL string) *Client {
+	return NewClientWithFingerprint(serverURL, "")
+}
+
+// NewClientWithFingerprint creates a new ZeroSpice client whose HTTP
+// transport retries on 5xx/network errors with exponential backoff,
+// trips a circuit breaker after repeated failures, logs every request,
+// and pins the server's TLS certificate to fingerprint when non-empty.
+func NewClientWithFingerprint(serverURL, fingerprint string) *Client {
+	transport := newRetryTransport(newTransport(fingerprint))
 	return &Client{
 		ServerURL: serverURL,
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   10 * time.Second,
+			Transport: newLoggingTransport(transport),
 		},
 	}
 }
@@ -135,6 +159,7 @@ func (c *Client) RefreshToken() error {
 	}
 
 	c.SessionToken = tokenReply.Token
+	c.LastRefresh = time.Now()
 	return nil
 }
 
@@ -213,56 +238,52 @@ func (c *Client) GetSpiceFile(node string, vmid int) (string, error) {
 	return string(body), nil
 }
 
-// LaunchSpiceViewer writes the SPICE file and launches the appropriate viewer
+// LaunchSpiceViewer picks a Viewer (c.PreferredViewer if set, otherwise
+// auto-detected from $PATH, falling back to the embedded browser viewer)
+// and uses it to open spiceText.
 func (c *Client) LaunchSpiceViewer(spiceText string) error {
-	spiceFile, err := os.CreateTemp("", "spice-*.vv")
-	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
-	}
-	defer os.Remove(spiceFile.Name())
-
-	_, err = spiceFile.Write([]byte(spiceText))
+	viewer, err := selectViewer(c.PreferredViewer)
 	if err != nil {
-		spiceFile.Close()
-		return fmt.Errorf("failed to write spice contents to temp file: %w", err)
+		return err
 	}
-	spiceFile.Close()
 
-	// Use appropriate viewer command based on OS
-	viewerCmd := "remote-viewer"
-	if runtime.GOOS == "windows" {
-		viewerCmd = "virt-viewer"
-	}
+	logger.Info("launching viewer", "viewer", viewer.Name())
+	return viewer.Launch(spiceText)
+}
 
-	cmd := exec.Command(viewerCmd, spiceFile.Name())
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to run %s: %w (ensure %s is installed)", viewerCmd, err, viewerCmd)
+// StartTokenRefreshLoop starts a goroutine that refreshes the token every
+// c.RefreshInterval (defaultRefreshInterval if unset). It returns stopChan,
+// which can be closed to stop the loop, and failChan, which receives the
+// error whenever a refresh attempt fails so callers can prompt for re-auth
+// instead of the goroutine silently dying. failChan is closed after it
+// delivers one error.
+func (c *Client) StartTokenRefreshLoop() (stopChan chan struct{}, failChan <-chan error) {
+	interval := c.RefreshInterval
+	if interval <= 0 {
+		interval = defaultRefreshInterval
 	}
 
-	return nil
-}
-
-// StartTokenRefreshLoop starts a goroutine that refreshes the token every 10 minutes
-// Returns a channel that can be closed to stop the refresh loop
-func (c *Client) StartTokenRefreshLoop() chan struct{} {
-	stopChan := make(chan struct{})
+	stop := make(chan struct{})
+	fail := make(chan error, 1)
 
 	go func() {
-		ticker := time.NewTicker(10 * time.Minute)
+		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
+		defer close(fail)
 
 		for {
 			select {
 			case <-ticker.C:
 				if err := c.RefreshToken(); err != nil {
-					fmt.Fprintf(os.Stderr, "Error refreshing token: %v\n", err)
+					logger.Error("token refresh failed", "error", err)
+					fail <- err
 					return
 				}
-			case <-stopChan:
+			case <-stop:
 				return
 			}
 		}
 	}()
 
-	return stopChan
+	return stop, fail
 }
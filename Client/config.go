@@ -4,11 +4,56 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sort"
 )
 
 // AppConfig stores persistent application settings
 type AppConfig struct {
 	ProxyURL string `json:"proxy_url"`
+
+	// Profiles holds named connection targets for multi-cluster use.
+	// ActiveProfile names the profile last used, so CLI runs without
+	// -profile can still pick up the right one.
+	Profiles      map[string]ServerProfile `json:"profiles,omitempty"`
+	ActiveProfile string                   `json:"active_profile,omitempty"`
+
+	// PreferredViewer names the viewer to try first: a binary name/path
+	// (e.g. "remote-viewer", "virt-manager") or "browser" for the
+	// embedded noVNC fallback. Empty means auto-detect.
+	PreferredViewer string `json:"preferred_viewer,omitempty"`
+
+	// RefreshIntervalMinutes overrides how often Client refreshes the
+	// session token in the background. Zero means use Client's default
+	// (10 minutes). Set from the GUI settings pane.
+	RefreshIntervalMinutes int `json:"refresh_interval_minutes,omitempty"`
+}
+
+// ServerProfile stores the connection details for a single named server.
+type ServerProfile struct {
+	ServerURL      string `json:"server_url"`
+	Username       string `json:"username,omitempty"`
+	PreferredNode  string `json:"preferred_node,omitempty"`
+	LastVMID       int    `json:"last_vmid,omitempty"`
+	TLSFingerprint string `json:"tls_fingerprint,omitempty"`
+	ViewerOverride string `json:"viewer_override,omitempty"`
+}
+
+// migrateLegacyProxyURL moves a pre-profile config's single ProxyURL
+// into a "default" profile, so configs written before profiles existed
+// keep working without the user re-entering anything.
+func migrateLegacyProxyURL(config *AppConfig) {
+	if config.ProxyURL == "" {
+		return
+	}
+	if config.Profiles == nil {
+		config.Profiles = make(map[string]ServerProfile)
+	}
+	if _, exists := config.Profiles["default"]; !exists {
+		config.Profiles["default"] = ServerProfile{ServerURL: config.ProxyURL}
+	}
+	if config.ActiveProfile == "" {
+		config.ActiveProfile = "default"
+	}
 }
 
 // getConfigPath returns the path to the config file
@@ -51,9 +96,31 @@ func LoadConfig() (*AppConfig, error) {
 		return &AppConfig{}, nil
 	}
 
+	migrateLegacyProxyURL(&config)
+
 	return &config, nil
 }
 
+// SaveProfile adds or updates a named profile and persists the config.
+func SaveProfile(config *AppConfig, name string, profile ServerProfile) error {
+	if config.Profiles == nil {
+		config.Profiles = make(map[string]ServerProfile)
+	}
+	config.Profiles[name] = profile
+	config.ActiveProfile = name
+	return SaveConfig(config)
+}
+
+// ListProfiles returns the configured profile names in alphabetical order.
+func ListProfiles(config *AppConfig) []string {
+	names := make([]string, 0, len(config.Profiles))
+	for name := range config.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // SaveConfig saves the application configuration to disk
 func SaveConfig(config *AppConfig) error {
 	configPath, err := getConfigPath()
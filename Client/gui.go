@@ -0,0 +1,400 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// statusRefreshInterval controls how often the main window's status bar
+// repaints with a fresh "token refreshed N ago" message.
+const statusRefreshInterval = 30 * time.Second
+
+// guiState holds the shared, mutable pieces of the GUI that need to
+// survive across windows (login -> main -> settings).
+type guiState struct {
+	app           fyne.App
+	client        *Client
+	config        *AppConfig
+	stopRefresh   chan struct{}
+	refreshFailed <-chan error
+
+	// statusBar and stopStatusWatch are set once showMainWindow runs, so
+	// showSettingsWindow can restart the refresh loop after the user
+	// changes the refresh interval and keep the status bar live.
+	statusBar       *widget.Label
+	stopStatusWatch func()
+}
+
+// startRefreshWatch forwards s.client's refresh outcomes to s.statusBar:
+// a periodic "last refreshed" message, or a terminal "session expired"
+// one. Call it again after restartRefreshLoop swaps in a new
+// refreshFailed channel.
+func (s *guiState) startRefreshWatch() {
+	ticker := time.NewTicker(statusRefreshInterval)
+	done := make(chan struct{})
+	var doneOnce sync.Once
+	stop := func() { doneOnce.Do(func() { close(done) }) }
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.statusBar.SetText(tokenRefreshStatus(s.client.LastRefresh))
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	go func() {
+		if err, ok := <-s.refreshFailed; ok {
+			stop()
+			s.statusBar.SetText(fmt.Sprintf("Session expired: %v — please reconnect", err))
+		}
+	}()
+
+	s.stopStatusWatch = stop
+}
+
+// restartRefreshLoop stops the running token-refresh goroutine and
+// starts a new one using c.RefreshInterval's current value, re-wiring
+// the status bar to the new failure channel. Used after the settings
+// pane changes the refresh interval.
+func (s *guiState) restartRefreshLoop() {
+	if s.stopStatusWatch != nil {
+		s.stopStatusWatch()
+	}
+	if s.stopRefresh != nil {
+		close(s.stopRefresh)
+	}
+	s.stopRefresh, s.refreshFailed = s.client.StartTokenRefreshLoop()
+	s.startRefreshWatch()
+}
+
+// runGUI launches the Fyne desktop application. It shares the same
+// Client and AppConfig types as CLI mode so a config saved from one
+// is immediately usable from the other.
+func runGUI(config *AppConfig) error {
+	a := app.New()
+	state := &guiState{app: a, config: config}
+
+	showLoginWindow(state)
+	a.Run()
+	return nil
+}
+
+// showLoginWindow prompts for a saved profile or server URL, username,
+// and OTP, then authenticates and hands off to the main VM browser
+// window. Profiles are shared with CLI mode via AppConfig, so a profile
+// saved from one is immediately selectable from the other.
+func showLoginWindow(state *guiState) {
+	w := state.app.NewWindow("ZeroSpice - Login")
+
+	serverEntry := widget.NewEntry()
+	serverEntry.SetPlaceHolder("https://proxmox.example.com")
+
+	usernameEntry := widget.NewEntry()
+	usernameEntry.SetPlaceHolder("username")
+
+	otpEntry := widget.NewPasswordEntry()
+	otpEntry.SetPlaceHolder("6-digit OTP")
+
+	profileNameEntry := widget.NewEntry()
+	profileNameEntry.SetPlaceHolder("Save as profile (optional)")
+
+	// selectedProfile tracks the profile backing the current form values,
+	// so Connect can reuse its TLSFingerprint/ViewerOverride as long as
+	// the user hasn't edited the server URL away from it.
+	var selectedProfile *ServerProfile
+
+	profileSelect := widget.NewSelect(ListProfiles(state.config), func(name string) {
+		profile, ok := state.config.Profiles[name]
+		if !ok {
+			return
+		}
+		selectedProfile = &profile
+		serverEntry.SetText(profile.ServerURL)
+		usernameEntry.SetText(profile.Username)
+		profileNameEntry.SetText(name)
+	})
+	profileSelect.PlaceHolder = "Saved profile..."
+
+	if state.config.ActiveProfile != "" {
+		profileSelect.SetSelected(state.config.ActiveProfile)
+	} else if state.config.ProxyURL != "" {
+		serverEntry.SetText(state.config.ProxyURL)
+	}
+
+	status := widget.NewLabel("")
+
+	loginButton := widget.NewButton("Connect", func() {
+		status.SetText("Checking server health...")
+
+		fingerprint := ""
+		viewer := state.config.PreferredViewer
+		if selectedProfile != nil && selectedProfile.ServerURL == serverEntry.Text {
+			fingerprint = selectedProfile.TLSFingerprint
+			if selectedProfile.ViewerOverride != "" {
+				viewer = selectedProfile.ViewerOverride
+			}
+		}
+
+		client := NewClientWithFingerprint(serverEntry.Text, fingerprint)
+		client.PreferredViewer = viewer
+		if err := client.CheckServerHealth(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		status.SetText("Authenticating...")
+		if err := client.Authenticate(usernameEntry.Text, otpEntry.Text); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		state.client = client
+		state.config.ProxyURL = serverEntry.Text
+
+		if name := strings.TrimSpace(profileNameEntry.Text); name != "" {
+			profile := ServerProfile{
+				ServerURL:      serverEntry.Text,
+				Username:       usernameEntry.Text,
+				TLSFingerprint: fingerprint,
+			}
+			if err := SaveProfile(state.config, name, profile); err != nil {
+				logger.Warn("failed to save profile", "profile", name, "error", err)
+			}
+		} else if err := SaveConfig(state.config); err != nil {
+			// Non-fatal: the session still works, just won't be remembered.
+			logger.Warn("failed to save config", "error", err)
+		}
+
+		if state.config.RefreshIntervalMinutes > 0 {
+			client.RefreshInterval = time.Duration(state.config.RefreshIntervalMinutes) * time.Minute
+		}
+		state.stopRefresh, state.refreshFailed = client.StartTokenRefreshLoop()
+
+		w.Close()
+		showMainWindow(state)
+	})
+
+	form := container.NewVBox(
+		widget.NewLabel("Saved profile"),
+		profileSelect,
+		widget.NewLabel("Server URL"),
+		serverEntry,
+		widget.NewLabel("Username"),
+		usernameEntry,
+		widget.NewLabel("OTP"),
+		otpEntry,
+		widget.NewLabel("Save as profile"),
+		profileNameEntry,
+		loginButton,
+		status,
+	)
+
+	w.SetContent(container.NewPadded(form))
+	w.Resize(fyne.NewSize(380, 420))
+	w.Show()
+}
+
+// showMainWindow lists VMs in a sortable, filterable table and wires
+// up the connect button and settings pane.
+func showMainWindow(state *guiState) {
+	w := state.app.NewWindow("ZeroSpice")
+
+	vms, err := state.client.GetVMs()
+	if err != nil {
+		dialog.ShowError(err, w)
+	}
+
+	filterEntry := widget.NewEntry()
+	filterEntry.SetPlaceHolder("Filter by name, node, or type...")
+
+	statusBar := widget.NewLabel(tokenRefreshStatus(state.client.LastRefresh))
+	state.statusBar = statusBar
+	state.startRefreshWatch()
+
+	list := widget.NewTable(
+		func() (int, int) { return len(vms) + 1, 4 },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, o fyne.CanvasObject) {
+			label := o.(*widget.Label)
+			if id.Row == 0 {
+				headers := []string{"Name", "Node", "Type", "VMID"}
+				label.SetText(headers[id.Col])
+				return
+			}
+			vm := vms[id.Row-1]
+			switch id.Col {
+			case 0:
+				label.SetText(vm.Name)
+			case 1:
+				label.SetText(vm.Node)
+			case 2:
+				label.SetText(vm.Type)
+			case 3:
+				label.SetText(fmt.Sprintf("%d", vm.VMID))
+			}
+		},
+	)
+
+	var selected *VM
+	list.OnSelected = func(id widget.TableCellID) {
+		if id.Row == 0 || id.Row-1 >= len(vms) {
+			return
+		}
+		vm := vms[id.Row-1]
+		selected = &vm
+	}
+
+	refreshList := func() {
+		filtered, err := state.client.GetVMs()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		vms = filterVMs(filtered, filterEntry.Text)
+		list.Refresh()
+	}
+	filterEntry.OnChanged = func(string) { refreshList() }
+
+	connectButton := widget.NewButton("Connect", func() {
+		if selected == nil {
+			dialog.ShowInformation("No VM selected", "Select a VM from the table first.", w)
+			return
+		}
+		spiceText, err := state.client.GetSpiceFile(selected.Node, selected.VMID)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if err := state.client.LaunchSpiceViewer(spiceText); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+	})
+
+	settingsButton := widget.NewButton("Settings", func() {
+		showSettingsWindow(state)
+	})
+
+	refreshButton := widget.NewButton("Refresh", func() { refreshList() })
+
+	toolbar := container.NewHBox(connectButton, refreshButton, settingsButton)
+	top := container.NewVBox(filterEntry, toolbar)
+
+	content := container.NewBorder(top, statusBar, nil, nil, list)
+	w.SetContent(content)
+	w.Resize(fyne.NewSize(640, 480))
+
+	w.SetOnClosed(func() {
+		if state.stopStatusWatch != nil {
+			state.stopStatusWatch()
+		}
+		if state.stopRefresh != nil {
+			close(state.stopRefresh)
+		}
+	})
+
+	w.Show()
+}
+
+// filterVMs returns the subset of vms whose name, node, or type
+// contains query (case-sensitive substring match, same as the CLI).
+func filterVMs(vms []VM, query string) []VM {
+	if query == "" {
+		return vms
+	}
+	var out []VM
+	for _, vm := range vms {
+		if strings.Contains(vm.Name, query) || strings.Contains(vm.Node, query) || strings.Contains(vm.Type, query) {
+			out = append(out, vm)
+		}
+	}
+	return out
+}
+
+// showSettingsWindow edits the AppConfig fields relevant to GUI mode
+// and persists them via SaveConfig, the same function used by CLI mode.
+func showSettingsWindow(state *guiState) {
+	w := state.app.NewWindow("ZeroSpice - Settings")
+
+	activeProfile := state.config.ActiveProfile
+	if activeProfile == "" {
+		activeProfile = "(none — using legacy proxy URL)"
+	}
+	profileLabel := widget.NewLabel(fmt.Sprintf("Active profile: %s", activeProfile))
+
+	proxyEntry := widget.NewEntry()
+	proxyEntry.SetText(state.config.ProxyURL)
+
+	viewerEntry := widget.NewEntry()
+	viewerEntry.SetPlaceHolder("/usr/bin/remote-viewer")
+	viewerEntry.SetText(state.config.PreferredViewer)
+
+	refreshMinutes := state.config.RefreshIntervalMinutes
+	if refreshMinutes <= 0 {
+		refreshMinutes = 10
+	}
+	refreshEntry := widget.NewEntry()
+	refreshEntry.SetText(strconv.Itoa(refreshMinutes))
+
+	saveButton := widget.NewButton("Save", func() {
+		minutes, err := strconv.Atoi(strings.TrimSpace(refreshEntry.Text))
+		if err != nil || minutes <= 0 {
+			dialog.ShowError(fmt.Errorf("refresh interval must be a positive number of minutes"), w)
+			return
+		}
+
+		state.config.ProxyURL = proxyEntry.Text
+		state.config.PreferredViewer = viewerEntry.Text
+		state.config.RefreshIntervalMinutes = minutes
+		if err := SaveConfig(state.config); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+
+		if state.client != nil {
+			state.client.PreferredViewer = viewerEntry.Text
+			state.client.RefreshInterval = time.Duration(minutes) * time.Minute
+			state.restartRefreshLoop()
+		}
+
+		w.Close()
+	})
+
+	form := container.NewVBox(
+		profileLabel,
+		widget.NewLabel("Proxy/Server URL"),
+		proxyEntry,
+		widget.NewLabel("Preferred viewer path"),
+		viewerEntry,
+		widget.NewLabel("Refresh interval (minutes)"),
+		refreshEntry,
+		saveButton,
+	)
+
+	w.SetContent(container.NewPadded(form))
+	w.Resize(fyne.NewSize(360, 280))
+	w.Show()
+}
+
+// tokenRefreshStatus formats a short human-readable description of how
+// long ago the session token was last refreshed, for the status bar.
+func tokenRefreshStatus(lastRefresh time.Time) string {
+	if lastRefresh.IsZero() {
+		return "Token refresh pending"
+	}
+	return fmt.Sprintf("Token refreshed %s ago", time.Since(lastRefresh).Round(time.Second))
+}
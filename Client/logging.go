@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"time"
+)
+
+// tokenFieldPattern matches a JSON "token":"..." field so it can be
+// masked before request/response bodies are logged in debug mode.
+var tokenFieldPattern = regexp.MustCompile(`"token"\s*:\s*"[^"]*"`)
+
+// logger is the package-wide structured logger, configured once by
+// setupLogging in main(). It defaults to an info-level text logger on
+// stderr so code paths that run before flag parsing still have somewhere
+// sane to write.
+var logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+// setupLogging builds the package logger from the -log-level, -log-format,
+// and -log-file flags and installs it as the package default.
+func setupLogging(level, format, file string) (*slog.Logger, error) {
+	var slevel slog.Level
+	switch level {
+	case "debug":
+		slevel = slog.LevelDebug
+	case "info", "":
+		slevel = slog.LevelInfo
+	case "warn":
+		slevel = slog.LevelWarn
+	case "error":
+		slevel = slog.LevelError
+	default:
+		return nil, fmt.Errorf("unknown log level %q (want debug, info, warn, or error)", level)
+	}
+
+	out := io.Writer(os.Stderr)
+	if file != "" {
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log file %s: %w", file, err)
+		}
+		out = f
+	}
+
+	opts := &slog.HandlerOptions{Level: slevel}
+	var handler slog.Handler
+	switch format {
+	case "json":
+		handler = slog.NewJSONHandler(out, opts)
+	case "text", "":
+		handler = slog.NewTextHandler(out, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q (want text or json)", format)
+	}
+
+	logger = slog.New(handler)
+	return logger, nil
+}
+
+// redactAuthHeader returns a copy of header with the Authorization value
+// masked, so request logs never contain a usable bearer token.
+func redactAuthHeader(header http.Header) string {
+	if header.Get("Authorization") == "" {
+		return ""
+	}
+	return "Bearer ***redacted***"
+}
+
+// redactToken replaces a JSON "token" field's value with a mask, for
+// debug-mode request/response body dumps.
+func redactToken(body string) string {
+	re := tokenFieldPattern
+	return re.ReplaceAllString(body, `"token":"***redacted***"`)
+}
+
+// loggingTransport is an http.RoundTripper that logs each request's
+// method, path, status, and latency at info level, and in debug mode
+// also dumps the request/response bodies with the token field masked.
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+func newLoggingTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &loggingTransport{next: next}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	var reqBody string
+	if logger.Enabled(req.Context(), slog.LevelDebug) && req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		if err == nil {
+			reqBody = redactToken(string(b))
+			req.Body = io.NopCloser(bytes.NewReader(b))
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	latency := time.Since(start)
+
+	if err != nil {
+		logger.Error("http request failed", "method", req.Method, "path", req.URL.Path, "error", err, "latency", latency)
+		return resp, err
+	}
+
+	logger.Info("http request", "method", req.Method, "path", req.URL.Path, "status", resp.StatusCode, "latency", latency, "auth", redactAuthHeader(req.Header))
+
+	if logger.Enabled(req.Context(), slog.LevelDebug) {
+		if reqBody != "" {
+			logger.Debug("http request body", "path", req.URL.Path, "body", reqBody)
+		}
+		b, err := io.ReadAll(resp.Body)
+		if err == nil {
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(b))
+			logger.Debug("http response body", "path", req.URL.Path, "body", redactToken(string(b)))
+		}
+	}
+
+	return resp, err
+}
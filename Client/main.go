@@ -17,9 +17,21 @@ func main() {
 	node := flag.String("node", "", "(Optional) The Proxmox node to access")
 	vmid := flag.Int("vmid", -1, "(Optional) The VMID to access")
 	guiMode := flag.Bool("gui", false, "Launch GUI mode")
+	profileName := flag.String("profile", "", "(Optional) Named server profile to connect with")
+	tlsFingerprint := flag.String("tls-fingerprint", "", "(Optional) SHA-256 fingerprint of the server's TLS certificate, for certificate pinning")
+	listProfiles := flag.Bool("list-profiles", false, "List saved server profiles and exit")
+	logLevel := flag.String("log-level", "info", "Log verbosity: debug, info, warn, or error")
+	logFormat := flag.String("log-format", "text", "Log output format: text or json")
+	logFile := flag.String("log-file", "", "(Optional) Path to write logs to, in addition to stderr")
+	plainMode := flag.Bool("plain", false, "Use the plain line-based VM prompt instead of the TUI browser")
 
 	flag.Parse()
 
+	if _, err := setupLogging(*logLevel, *logFormat, *logFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Check for unknown arguments
 	if len(flag.Args()) > 0 {
 		fmt.Fprintf(os.Stderr, "Error: Unknown arguments: %v\n\n", flag.Args())
@@ -27,13 +39,6 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Determine mode: GUI or CLI
-	if *guiMode {
-		// TODO: Launch GUI when implemented
-		fmt.Println("GUI mode not yet implemented")
-		os.Exit(1)
-	}
-
 	// Load config to check for saved server URL
 	config, err := LoadConfig()
 	if err != nil {
@@ -41,83 +46,238 @@ func main() {
 		config = &AppConfig{}
 	}
 
-	// Determine server URL: CLI flag takes precedence, then config file
+	if *listProfiles {
+		printProfiles(config)
+		return
+	}
+
+	// Determine mode: GUI or CLI
+	if *guiMode {
+		if err := runGUI(config); err != nil {
+			logger.Error("gui mode failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Determine server URL: -profile, then -server flag, then the active
+	// profile from config, then the legacy config.ProxyURL. activeProfile
+	// tracks which profile (if any) supplied it, so runCLI can write
+	// LastVMID back to the right one once a VM is chosen.
 	serverURL := *server
-	if serverURL == "" && config.ProxyURL != "" {
-		serverURL = config.ProxyURL
-		fmt.Printf("[*] Using server URL from config: %s\n", serverURL)
+	var activeProfile string
+	var viewerOverride string
+	if *profileName != "" {
+		profile, ok := config.Profiles[*profileName]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: no saved profile named %q (see -list-profiles)\n", *profileName)
+			os.Exit(1)
+		}
+		serverURL = profile.ServerURL
+		if *username == "" {
+			*username = profile.Username
+		}
+		if *node == "" {
+			*node = profile.PreferredNode
+		}
+		if *tlsFingerprint == "" {
+			*tlsFingerprint = profile.TLSFingerprint
+		}
+		if *vmid == -1 && profile.LastVMID != 0 {
+			*vmid = profile.LastVMID
+		}
+		viewerOverride = profile.ViewerOverride
+		activeProfile = *profileName
+		logger.Info("using saved profile", "profile", *profileName, "server", serverURL)
+	} else if serverURL == "" {
+		if profile, ok := config.Profiles[config.ActiveProfile]; config.ActiveProfile != "" && ok {
+			serverURL = profile.ServerURL
+			if *username == "" {
+				*username = profile.Username
+			}
+			if *node == "" {
+				*node = profile.PreferredNode
+			}
+			if *tlsFingerprint == "" {
+				*tlsFingerprint = profile.TLSFingerprint
+			}
+			if *vmid == -1 && profile.LastVMID != 0 {
+				*vmid = profile.LastVMID
+			}
+			viewerOverride = profile.ViewerOverride
+			activeProfile = config.ActiveProfile
+			logger.Info("using active profile", "profile", config.ActiveProfile, "server", serverURL)
+		} else if config.ProxyURL != "" {
+			serverURL = config.ProxyURL
+			logger.Info("using server URL from config", "server", serverURL)
+		}
 	}
 
 	// CLI mode - validate required arguments
 	if serverURL == "" {
-		fmt.Fprintf(os.Stderr, "Error: server URL required (use -server flag or save to config)\n\n")
+		fmt.Fprintf(os.Stderr, "Error: server URL required (use -server flag, -profile, or save to config)\n\n")
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
 
 	// Run CLI mode
-	runCLI(serverURL, *username, *node, *vmid, config)
+	runCLI(serverURL, *username, *node, *vmid, *tlsFingerprint, viewerOverride, activeProfile, config, *plainMode)
 }
 
-func runCLI(serverURL, username, node string, vmid int, config *AppConfig) {
+// printProfiles prints each saved profile's name and server URL.
+func printProfiles(config *AppConfig) {
+	names := ListProfiles(config)
+	if len(names) == 0 {
+		fmt.Println("No saved profiles.")
+		return
+	}
+	fmt.Println("NAME\t\tSERVER")
+	for _, name := range names {
+		fmt.Printf("%s\t\t%s\n", name, config.Profiles[name].ServerURL)
+	}
+}
+
+func runCLI(serverURL, username, node string, vmid int, tlsFingerprint, viewerOverride, activeProfile string, config *AppConfig, plain bool) {
 	// Create client
-	client := NewClient(serverURL)
+	client := NewClientWithFingerprint(serverURL, tlsFingerprint)
+	client.PreferredViewer = config.PreferredViewer
+	if viewerOverride != "" {
+		client.PreferredViewer = viewerOverride
+	}
+	if config.RefreshIntervalMinutes > 0 {
+		client.RefreshInterval = time.Duration(config.RefreshIntervalMinutes) * time.Minute
+	}
+
+	// recordLastVMID remembers the most recently connected VMID on
+	// activeProfile, so the next run of -profile <name> can reconnect to
+	// it without specifying -vmid.
+	recordLastVMID := func(vmid int) {
+		if activeProfile == "" {
+			return
+		}
+		profile := config.Profiles[activeProfile]
+		if profile.LastVMID == vmid {
+			return
+		}
+		profile.LastVMID = vmid
+		config.Profiles[activeProfile] = profile
+		if err := SaveConfig(config); err != nil {
+			logger.Warn("failed to save profile", "profile", activeProfile, "error", err)
+		}
+	}
 
 	// Check server health
-	fmt.Println("[*] Checking server health...")
+	logger.Info("checking server health")
 	if err := client.CheckServerHealth(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		logger.Error("server health check failed", "error", err)
 		os.Exit(1)
 	}
-	fmt.Println("[*] Passed server health check")
+	logger.Info("passed server health check")
 
 	// Save server URL to config on successful health check
 	if config.ProxyURL != serverURL {
 		config.ProxyURL = serverURL
 		if err := SaveConfig(config); err != nil {
 			// Non-fatal, just warn
-			fmt.Fprintf(os.Stderr, "Warning: failed to save config: %v\n", err)
+			logger.Warn("failed to save config", "error", err)
 		}
 	}
 
 	// Authenticate
 	if err := authenticateUser(client, username); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		logger.Error("authentication failed", "error", err)
 		os.Exit(1)
 	}
 
+	maybeSaveNewProfile(config, serverURL, username, tlsFingerprint)
+
 	// Start token refresh loop
-	stopRefresh := client.StartTokenRefreshLoop()
+	stopRefresh, refreshFailed := client.StartTokenRefreshLoop()
 	defer close(stopRefresh)
+	go func() {
+		if err, ok := <-refreshFailed; ok {
+			logger.Error("session expired, please restart and re-authenticate", "error", err)
+		}
+	}()
 
 	// If node and vmid specified, connect directly
 	if vmid != -1 && node != "" {
 		if err := connectToVM(client, node, vmid); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			logger.Error("failed to connect to VM", "node", node, "vmid", vmid, "error", err)
 			os.Exit(1)
 		}
+		recordLastVMID(vmid)
 		return
 	}
 
-	// Interactive mode: loop through VM selection
+	// Interactive mode: use the TUI browser by default when stdout is a
+	// TTY; fall back to the plain line-based prompt for scripts or -plain.
+	useTUI := !plain && isInteractiveTTY()
+
 	for {
 		vms, err := client.GetVMs()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			logger.Error("failed to fetch VMs", "error", err)
 			os.Exit(1)
 		}
 
+		if useTUI {
+			selectedNode, selectedVMID, ok, tuiErr := runTUI(client, vms)
+			if tuiErr != nil {
+				logger.Error("vm selection failed", "error", tuiErr)
+				os.Exit(1)
+			}
+			if !ok {
+				fmt.Println("Exiting...")
+				os.Exit(0)
+			}
+			if err := connectToVM(client, selectedNode, selectedVMID); err != nil {
+				logger.Error("failed to connect to VM", "node", selectedNode, "vmid", selectedVMID, "error", err)
+			} else {
+				recordLastVMID(selectedVMID)
+			}
+			continue
+		}
+
 		selectedNode, selectedVMID, err := selectVM(vms)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			logger.Error("vm selection failed", "error", err)
 			os.Exit(1)
 		}
 
 		if err := connectToVM(client, selectedNode, selectedVMID); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			logger.Error("failed to connect to VM", "node", selectedNode, "vmid", selectedVMID, "error", err)
 			// Don't exit, allow user to try another VM
+		} else {
+			recordLastVMID(selectedVMID)
+		}
+	}
+}
+
+// maybeSaveNewProfile offers to save serverURL as a named profile the
+// first time it's seen, so future runs can use -profile instead of
+// retyping the URL.
+func maybeSaveNewProfile(config *AppConfig, serverURL, username, tlsFingerprint string) {
+	for _, profile := range config.Profiles {
+		if profile.ServerURL == serverURL {
+			return
 		}
 	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("[>] Save this server as a named profile? (enter a name, or leave blank to skip): ")
+	name, _ := reader.ReadString('\n')
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+
+	profile := ServerProfile{ServerURL: serverURL, Username: username, TLSFingerprint: tlsFingerprint}
+	if err := SaveProfile(config, name, profile); err != nil {
+		logger.Warn("failed to save profile", "profile", name, "error", err)
+		return
+	}
+	fmt.Printf("[*] Saved profile %q\n", name)
 }
 
 func authenticateUser(client *Client, username string) error {
@@ -142,7 +302,7 @@ func authenticateUser(client *Client, username string) error {
 		return err
 	}
 
-	fmt.Println("[*] Authentication successful")
+	logger.Info("authentication successful", "username", username)
 	return nil
 }
 
@@ -174,19 +334,19 @@ func selectVM(vms []VM) (string, int, error) {
 }
 
 func connectToVM(client *Client, node string, vmid int) error {
-	fmt.Printf("[*] Connecting to VM %d on node %s...\n", vmid, node)
+	logger.Info("connecting to VM", "node", node, "vmid", vmid)
 
 	spiceText, err := client.GetSpiceFile(node, vmid)
 	if err != nil {
 		return err
 	}
 
-	fmt.Println("[*] Launching SPICE viewer...")
+	logger.Info("launching SPICE viewer")
 	if err := client.LaunchSpiceViewer(spiceText); err != nil {
 		return err
 	}
 
-	fmt.Println("[*] SPICE viewer launched successfully")
+	logger.Info("SPICE viewer launched successfully")
 	// Give viewer a moment to start before potentially showing menu again
 	time.Sleep(1 * time.Second)
 	return nil
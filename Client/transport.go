@@ -0,0 +1,185 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	maxRetries             = 3
+	initialBackoff         = 500 * time.Millisecond
+	maxBackoff             = 8 * time.Second
+	circuitBreakerLimit    = 5
+	circuitBreakerCooldown = 30 * time.Second
+)
+
+// retryTransport wraps an http.RoundTripper with exponential backoff
+// retries on 5xx responses and network errors, and trips a circuit
+// breaker after too many consecutive failures so a dead server doesn't
+// get hammered with retries.
+type retryTransport struct {
+	next    http.RoundTripper
+	breaker *circuitBreaker
+}
+
+func newRetryTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &retryTransport{next: next, breaker: &circuitBreaker{}}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if open, remaining := t.breaker.open(); open {
+		return nil, fmt.Errorf("circuit breaker open, retrying in %s", remaining)
+	}
+
+	var lastErr error
+	var wait time.Duration
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(wait)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, fmt.Errorf("failed to rewind request body for retry: %w", err)
+				}
+				req.Body = body
+			}
+		}
+
+		resp, err := t.next.RoundTrip(req)
+		if err == nil && resp.StatusCode < 500 {
+			t.breaker.recordSuccess()
+			return resp, nil
+		}
+
+		if err != nil {
+			lastErr = err
+			wait = backoffDuration(attempt + 1)
+		} else {
+			lastErr = fmt.Errorf("server returned status %d", resp.StatusCode)
+			if retryAfter, ok := retryAfterDelay(resp); ok {
+				wait = retryAfter
+			} else {
+				wait = backoffDuration(attempt + 1)
+			}
+			resp.Body.Close()
+		}
+
+		t.breaker.recordFailure()
+	}
+
+	return nil, fmt.Errorf("request failed after %d attempts: %w", maxRetries+1, lastErr)
+}
+
+// backoffDuration computes the exponential backoff delay for a retry attempt.
+func backoffDuration(attempt int) time.Duration {
+	delay := initialBackoff * time.Duration(math.Pow(2, float64(attempt-1)))
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
+
+// retryAfterDelay parses a Retry-After header (either seconds or an
+// HTTP-date) and returns how long to wait before the next attempt.
+func retryAfterDelay(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		return time.Until(when), true
+	}
+	return 0, false
+}
+
+// circuitBreaker trips after circuitBreakerLimit consecutive failures
+// and refuses new requests until circuitBreakerCooldown has elapsed.
+type circuitBreaker struct {
+	mu              sync.Mutex
+	consecutiveFail int
+	trippedAt       time.Time
+}
+
+func (b *circuitBreaker) recordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.consecutiveFail++
+	if b.consecutiveFail >= circuitBreakerLimit && b.trippedAt.IsZero() {
+		b.trippedAt = time.Now()
+	}
+}
+
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.consecutiveFail = 0
+	b.trippedAt = time.Time{}
+}
+
+// open reports whether the breaker is currently tripped, and if so how
+// much longer until it resets.
+func (b *circuitBreaker) open() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.trippedAt.IsZero() {
+		return false, 0
+	}
+	elapsed := time.Since(b.trippedAt)
+	if elapsed >= circuitBreakerCooldown {
+		b.consecutiveFail = 0
+		b.trippedAt = time.Time{}
+		return false, 0
+	}
+	return true, circuitBreakerCooldown - elapsed
+}
+
+// pinnedTLSConfig builds a tls.Config that rejects any certificate whose
+// SHA-256 fingerprint doesn't match the one pinned in AppConfig. An empty
+// fingerprint disables pinning and falls back to normal certificate
+// verification.
+func pinnedTLSConfig(fingerprint string) *tls.Config {
+	if fingerprint == "" {
+		return nil
+	}
+
+	return &tls.Config{
+		InsecureSkipVerify: true, // we verify the fingerprint ourselves below
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return fmt.Errorf("no peer certificates presented")
+			}
+			sum := sha256.Sum256(cs.PeerCertificates[0].Raw)
+			got := hex.EncodeToString(sum[:])
+			if got != fingerprint {
+				return fmt.Errorf("TLS certificate fingerprint mismatch: got %s, want %s", got, fingerprint)
+			}
+			return nil
+		},
+	}
+}
+
+// newTransport builds the base *http.Transport used by Client, applying
+// TLS pinning when fingerprint is non-empty.
+func newTransport(fingerprint string) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg := pinnedTLSConfig(fingerprint); cfg != nil {
+		transport.TLSClientConfig = cfg
+	}
+	return transport
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+)
+
+// vmRefreshInterval controls how often the TUI repolls /offer in the background.
+const vmRefreshInterval = 15 * time.Second
+
+// vmItem adapts VM to the bubbles/list.Item interface.
+type vmItem struct{ vm VM }
+
+func (i vmItem) Title() string { return i.vm.Name }
+func (i vmItem) Description() string {
+	return fmt.Sprintf("node=%s type=%s vmid=%d", i.vm.Node, i.vm.Type, i.vm.VMID)
+}
+func (i vmItem) FilterValue() string {
+	return i.vm.Name + " " + i.vm.Node + " " + i.vm.Type
+}
+
+type refreshMsg struct {
+	vms []VM
+	err error
+}
+
+// tuiModel is the Bubble Tea model for the VM browser: a filterable
+// list on the left, and a detail pane for the selected VM on the right.
+type tuiModel struct {
+	client   *Client
+	list     list.Model
+	err      error
+	quitting bool
+	selected *VM
+}
+
+func newTUIModel(client *Client, vms []VM) tuiModel {
+	items := make([]list.Item, len(vms))
+	for i, vm := range vms {
+		items[i] = vmItem{vm: vm}
+	}
+
+	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "ZeroSpice VMs"
+	l.SetFilteringEnabled(true)
+
+	return tuiModel{client: client, list: l}
+}
+
+func (m tuiModel) Init() tea.Cmd {
+	return tea.Tick(vmRefreshInterval, func(time.Time) tea.Msg { return pollVMs(m.client) })
+}
+
+func pollVMs(client *Client) tea.Msg {
+	vms, err := client.GetVMs()
+	return refreshMsg{vms: vms, err: err}
+}
+
+func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.list.SetSize(msg.Width, msg.Height-2)
+		return m, nil
+
+	case refreshMsg:
+		if msg.err != nil {
+			m.err = msg.err
+		} else {
+			items := make([]list.Item, len(msg.vms))
+			for i, vm := range msg.vms {
+				items[i] = vmItem{vm: vm}
+			}
+			m.list.SetItems(items)
+		}
+		return m, tea.Tick(vmRefreshInterval, func(time.Time) tea.Msg { return pollVMs(m.client) })
+
+	case tea.KeyMsg:
+		// Don't intercept keys while the user is typing a filter query.
+		if m.list.FilterState() == list.Filtering {
+			break
+		}
+		switch msg.String() {
+		case "q", "ctrl+c":
+			m.quitting = true
+			return m, tea.Quit
+		case "r":
+			return m, func() tea.Msg { return pollVMs(m.client) }
+		case "enter":
+			if item, ok := m.list.SelectedItem().(vmItem); ok {
+				vm := item.vm
+				m.selected = &vm
+				m.quitting = true
+				return m, tea.Quit
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m tuiModel) View() string {
+	if m.quitting {
+		return ""
+	}
+	footer := "\n[enter] connect  [r] refresh  [q] quit"
+	if m.err != nil {
+		footer = fmt.Sprintf("\nerror polling VMs: %v%s", m.err, footer)
+	}
+	return m.list.View() + footer
+}
+
+// runTUI shows the Bubble Tea VM browser and returns the node/VMID the
+// user chose to connect to. It returns (ok=false) if the user quit
+// without selecting a VM.
+func runTUI(client *Client, vms []VM) (node string, vmid int, ok bool, err error) {
+	program := tea.NewProgram(newTUIModel(client, vms), tea.WithAltScreen())
+	finalModel, err := program.Run()
+	if err != nil {
+		return "", -1, false, fmt.Errorf("tui failed: %w", err)
+	}
+
+	m := finalModel.(tuiModel)
+	if m.selected == nil {
+		return "", -1, false, nil
+	}
+	return m.selected.Node, m.selected.VMID, true, nil
+}
+
+// isInteractiveTTY reports whether stdout is an interactive terminal,
+// used to decide whether the TUI or the -plain line prompt should run.
+func isInteractiveTTY() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// browserConnectTimeout bounds how long Launch waits for the browser to
+// actually open the proxied WebSocket connection before giving up.
+const browserConnectTimeout = 20 * time.Second
+
+// browserViewerName selects the embedded noVNC fallback when used as
+// AppConfig.PreferredViewer.
+const browserViewerName = "browser"
+
+// Viewer launches a SPICE session from the contents of a .vv file.
+// Each implementation covers one way of actually showing the desktop
+// to the user: a native subprocess, or an embedded browser fallback.
+type Viewer interface {
+	// Name identifies the viewer for logs and error messages.
+	Name() string
+	// Available reports whether this viewer can be used on this machine.
+	Available() bool
+	// Launch opens spiceText's connection. It blocks until the viewer
+	// process exits (native viewers) or the proxied browser session
+	// ends (browser viewer).
+	Launch(spiceText string) error
+}
+
+// remoteViewerLauncher runs virt-viewer/remote-viewer as a subprocess.
+type remoteViewerLauncher struct {
+	binary string
+}
+
+func (r remoteViewerLauncher) Name() string { return r.binary }
+
+func (r remoteViewerLauncher) Available() bool {
+	_, err := exec.LookPath(r.binary)
+	return err == nil
+}
+
+func (r remoteViewerLauncher) Launch(spiceText string) error {
+	spiceFile, err := writeSpiceTempFile(spiceText)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(spiceFile)
+
+	cmd := exec.Command(r.binary, spiceFile)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run %s: %w", r.binary, err)
+	}
+	return nil
+}
+
+// virtManagerLauncher opens the connection in virt-manager.
+type virtManagerLauncher struct{}
+
+func (v virtManagerLauncher) Name() string { return "virt-manager" }
+
+func (v virtManagerLauncher) Available() bool {
+	_, err := exec.LookPath("virt-manager")
+	return err == nil
+}
+
+func (v virtManagerLauncher) Launch(spiceText string) error {
+	spiceFile, err := writeSpiceTempFile(spiceText)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(spiceFile)
+
+	cmd := exec.Command("virt-manager", "--connection-file", spiceFile)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run virt-manager: %w", err)
+	}
+	return nil
+}
+
+// browserViewer serves a noVNC/spice-html5 page locally and opens it in
+// the user's default browser, for machines with no native viewer
+// installed.
+type browserViewer struct{}
+
+func (b browserViewer) Name() string { return browserViewerName }
+
+// Available is always true: the browser fallback only needs a default
+// browser, which openBrowser already handles failing gracefully for.
+func (b browserViewer) Available() bool { return true }
+
+func (b browserViewer) Launch(spiceText string) error {
+	info, err := parseSpiceFile(spiceText)
+	if err != nil {
+		return fmt.Errorf("failed to parse spice file for browser viewer: %w", err)
+	}
+
+	server, url, session, err := startNoVNCProxy(info)
+	if err != nil {
+		return fmt.Errorf("failed to start noVNC proxy: %w", err)
+	}
+	defer server.Close()
+
+	if err := openBrowser(url); err != nil {
+		return fmt.Errorf("failed to open browser: %w", err)
+	}
+
+	// Block for the life of the proxied session, the same way the native
+	// viewers above block until their subprocess exits. If the browser
+	// never opens the WebSocket at all, give up after browserConnectTimeout
+	// instead of hanging forever.
+	select {
+	case <-session.connected:
+		<-session.done
+	case <-session.done:
+		// Upgrade or dial failed before ever reaching "connected".
+	case <-time.After(browserConnectTimeout):
+		return fmt.Errorf("timed out after %s waiting for the browser to connect to %s", browserConnectTimeout, url)
+	}
+
+	return nil
+}
+
+// spiceConnInfo is the subset of a .vv file needed to drive the
+// browser-based noVNC/spice-html5 fallback.
+type spiceConnInfo struct {
+	Host     string
+	Port     string
+	Password string
+}
+
+// parseSpiceFile extracts host, port, and password from a .vv file's
+// "[virt-viewer]" section. The format is a simple INI file, so this
+// avoids pulling in an INI parsing dependency for three fields.
+func parseSpiceFile(spiceText string) (*spiceConnInfo, error) {
+	info := &spiceConnInfo{}
+	scanner := bufio.NewScanner(strings.NewReader(spiceText))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch key {
+		case "host":
+			info.Host = value
+		case "port":
+			info.Port = value
+		case "password":
+			info.Password = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if info.Host == "" || info.Port == "" {
+		return nil, fmt.Errorf("spice file missing host or port")
+	}
+	return info, nil
+}
+
+// wsUpgrader upgrades the noVNC page's WebSocket request. Origin checks
+// are skipped because the server only ever listens on 127.0.0.1 and only
+// ever serves the one page it generates itself.
+var wsUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// proxySession tracks the lifetime of a single browser-viewer proxy: it
+// lets Launch block until the browser actually opens the WebSocket
+// (connected) and again until that connection ends (done).
+type proxySession struct {
+	connected chan struct{}
+	done      chan struct{}
+}
+
+// startNoVNCProxy starts a local HTTP server that serves a spice-html5
+// page wired to connect back to "/ws", and bridges that WebSocket to the
+// real SPICE TCP endpoint described by info. Callers are responsible for
+// closing the returned server once the session (reported via the
+// returned proxySession) ends.
+func startNoVNCProxy(info *spiceConnInfo) (*http.Server, string, *proxySession, error) {
+	session := &proxySession{connected: make(chan struct{}), done: make(chan struct{})}
+	var connectOnce, doneOnce sync.Once
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, noVNCPageTemplate, info.Password)
+	})
+	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		defer doneOnce.Do(func() { close(session.done) })
+
+		ws, err := wsUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			logger.Error("websocket upgrade failed", "error", err)
+			return
+		}
+		defer ws.Close()
+
+		tcpConn, err := net.Dial("tcp", net.JoinHostPort(info.Host, info.Port))
+		if err != nil {
+			logger.Error("failed to dial spice server", "host", info.Host, "port", info.Port, "error", err)
+			return
+		}
+		defer tcpConn.Close()
+
+		connectOnce.Do(func() { close(session.connected) })
+		proxyWebSocketToTCP(ws, tcpConn)
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+
+	url := fmt.Sprintf("http://%s/", listener.Addr().String())
+	return server, url, session, nil
+}
+
+// proxyWebSocketToTCP pumps bytes in both directions between ws and
+// tcpConn until either side closes or errors.
+func proxyWebSocketToTCP(ws *websocket.Conn, tcpConn net.Conn) {
+	errc := make(chan error, 2)
+
+	go func() {
+		for {
+			_, data, err := ws.ReadMessage()
+			if err != nil {
+				errc <- err
+				return
+			}
+			if _, err := tcpConn.Write(data); err != nil {
+				errc <- err
+				return
+			}
+		}
+	}()
+
+	go func() {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := tcpConn.Read(buf)
+			if n > 0 {
+				if werr := ws.WriteMessage(websocket.BinaryMessage, buf[:n]); werr != nil {
+					errc <- werr
+					return
+				}
+			}
+			if err != nil {
+				if err != io.EOF {
+					logger.Debug("spice tcp connection closed", "error", err)
+				}
+				errc <- err
+				return
+			}
+		}
+	}()
+
+	<-errc
+}
+
+// noVNCPageTemplate is a minimal spice-html5 page. It connects back to
+// this same server's "/ws" endpoint, which bridges the WebSocket to the
+// real SPICE TCP port — the browser never talks to the SPICE port
+// directly.
+const noVNCPageTemplate = `<!DOCTYPE html>
+<html>
+<head><title>ZeroSpice</title></head>
+<body>
+<div id="spice-area"></div>
+<script src="https://cdn.jsdelivr.net/npm/spice-html5/src/main.js"></script>
+<script>
+  var loc = window.location;
+  var sc = new SpiceMainConn({
+    uri: (loc.protocol === 'https:' ? 'wss://' : 'ws://') + loc.host + '/ws',
+    password: '%s',
+    screen_id: 'spice-area',
+  });
+</script>
+</body>
+</html>`
+
+// writeSpiceTempFile writes spiceText to a temp .vv file and returns its path.
+func writeSpiceTempFile(spiceText string) (string, error) {
+	spiceFile, err := os.CreateTemp("", "spice-*.vv")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer spiceFile.Close()
+
+	if _, err := spiceFile.Write([]byte(spiceText)); err != nil {
+		return "", fmt.Errorf("failed to write spice contents to temp file: %w", err)
+	}
+	return spiceFile.Name(), nil
+}
+
+// openBrowser opens url in the user's default browser, equivalent to
+// the behavior of the common "open" package (open.Run).
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+// candidateViewers returns, in priority order, the viewers to try:
+// preferred first (if set), then the platform's native subprocess
+// launchers, then the browser fallback as a last resort.
+func candidateViewers(preferred string) []Viewer {
+	native := remoteViewerLauncher{binary: "remote-viewer"}
+	if runtime.GOOS == "windows" {
+		native = remoteViewerLauncher{binary: "virt-viewer"}
+	}
+
+	all := []Viewer{native, virtManagerLauncher{}, browserViewer{}}
+
+	if preferred == "" {
+		return all
+	}
+
+	var ordered []Viewer
+	for _, v := range all {
+		if v.Name() == preferred {
+			ordered = append(ordered, v)
+		}
+	}
+	for _, v := range all {
+		if v.Name() != preferred {
+			ordered = append(ordered, v)
+		}
+	}
+	return ordered
+}
+
+// selectViewer probes candidates in order and returns the first one
+// available. If none are available (the browser fallback is always
+// available, so this only happens if preferred names an unknown
+// viewer), it returns an error listing every candidate tried.
+func selectViewer(preferred string) (Viewer, error) {
+	candidates := candidateViewers(preferred)
+	var tried []string
+	for _, v := range candidates {
+		tried = append(tried, v.Name())
+		if v.Available() {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("no usable SPICE viewer found (tried: %s)", strings.Join(tried, ", "))
+}